@@ -0,0 +1,41 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eviction does not yet contain a full eviction manager in this
+// tree: there is no synchronize loop, threshold config, or pod-ranking code
+// here for the PVC quota signal below to join. Signal and signalObservation
+// are the minimal shapes a future eviction manager would use to represent a
+// pressure signal; PVCQuotaObservations and PVCsOverQuota in yrfs_quota.go
+// are the entry points such a manager would call, exercised for now only by
+// this package's own tests.
+package eviction
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Signal identifies a resource that can be observed for eviction pressure,
+// such as "memory.available" or "nodefs.available".
+type Signal string
+
+// signalObservation is what was observed for a given eviction signal at a
+// point in time.
+type signalObservation struct {
+	available *resource.Quantity
+	capacity  *resource.Quantity
+	time      metav1.Time
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/kubernetes/pkg/volume"
+)
+
+// SignalPVCQuotaAvailable identifies the headroom remaining in a
+// PersistentVolume's yrfs project quota, for a future eviction manager to
+// compare against a configured threshold and trigger DiskPressure the way it
+// already does for the node filesystem as a whole.
+const SignalPVCQuotaAvailable Signal = "pvc.yrfsQuota.available"
+
+// quotaPressureObservation converts a volume.QuotaMetrics reading into a
+// signalObservation, using the same available/capacity shape the rest of
+// this package's signals use.
+func quotaPressureObservation(qm *volume.QuotaMetrics) signalObservation {
+	capacity := qm.QuotaBytes
+	available := resource.NewQuantity(capacity.Value()-qm.QuotaBytesUsed.Value(), resource.BinarySI)
+	return signalObservation{
+		available: available,
+		capacity:  capacity,
+		time:      qm.Time,
+	}
+}
+
+// quotaPressureSignalForProvider returns the SignalPVCQuotaAvailable
+// observation for provider if it also implements
+// volume.QuotaMetricsProvider, reporting ok=false for volumes that don't
+// surface a project quota.
+func quotaPressureSignalForProvider(provider volume.MetricsProvider) (signalObservation, bool) {
+	quotaProvider, ok := provider.(volume.QuotaMetricsProvider)
+	if !ok {
+		return signalObservation{}, false
+	}
+	qm, err := quotaProvider.GetQuotaMetrics()
+	if err != nil {
+		return signalObservation{}, false
+	}
+	return quotaPressureObservation(qm), true
+}
+
+// PVCQuotaObservations returns a SignalPVCQuotaAvailable observation for
+// every PVC-backed volume in providers whose MetricsProvider also
+// implements volume.QuotaMetricsProvider, keyed by volume name. Volumes
+// without a project quota configured, or that aren't yrfs-backed at all,
+// are omitted. This is the entry point a node-level eviction manager would
+// call each sync interval to fold quota pressure into its other signals;
+// no such manager exists in this tree yet, so it is currently only called
+// from this package's tests.
+func PVCQuotaObservations(providers map[string]volume.MetricsProvider) map[string]signalObservation {
+	observations := make(map[string]signalObservation, len(providers))
+	for name, provider := range providers {
+		if observation, ok := quotaPressureSignalForProvider(provider); ok {
+			observations[name] = observation
+		}
+	}
+	return observations
+}
+
+// thresholdMet reports whether observation's available headroom has fallen
+// below threshold.
+func thresholdMet(observation signalObservation, threshold resource.Quantity) bool {
+	if observation.available == nil {
+		return false
+	}
+	return observation.available.Cmp(threshold) < 0
+}
+
+// PVCsOverQuota returns the names of every volume in observations whose yrfs
+// project quota has less headroom remaining than threshold. A future eviction
+// manager would call this to decide which SignalPVCQuotaAvailable
+// observations should contribute to the node's DiskPressure condition and,
+// in turn, to pod eviction ordering.
+func PVCsOverQuota(observations map[string]signalObservation, threshold resource.Quantity) []string {
+	var overQuota []string
+	for name, observation := range observations {
+		if thresholdMet(observation, threshold) {
+			overQuota = append(overQuota, name)
+		}
+	}
+	return overQuota
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eviction
+
+import (
+	"sort"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/volume"
+)
+
+// fakeMetricsProvider is a minimal volume.MetricsProvider that reports no
+// quota information, standing in for a PV whose storage backend isn't yrfs.
+type fakeMetricsProvider struct{}
+
+func (fakeMetricsProvider) GetMetrics() (*volume.Metrics, error) {
+	return &volume.Metrics{}, nil
+}
+
+// fakeQuotaMetricsProvider additionally implements volume.QuotaMetricsProvider,
+// standing in for a yrfs-backed PV.
+type fakeQuotaMetricsProvider struct {
+	fakeMetricsProvider
+	quotaBytes     int64
+	quotaBytesUsed int64
+}
+
+func (f fakeQuotaMetricsProvider) GetQuotaMetrics() (*volume.QuotaMetrics, error) {
+	return &volume.QuotaMetrics{
+		Time:            metav1.Now(),
+		QuotaBytes:      resource.NewQuantity(f.quotaBytes, resource.BinarySI),
+		QuotaBytesUsed:  resource.NewQuantity(f.quotaBytesUsed, resource.BinarySI),
+		QuotaInodes:     resource.NewQuantity(0, resource.BinarySI),
+		QuotaInodesUsed: resource.NewQuantity(0, resource.BinarySI),
+	}, nil
+}
+
+func TestPVCQuotaObservationsSkipsNonQuotaVolumes(t *testing.T) {
+	providers := map[string]volume.MetricsProvider{
+		"pv-plain": fakeMetricsProvider{},
+		"pv-yrfs":  fakeQuotaMetricsProvider{quotaBytes: 10 << 30, quotaBytesUsed: 1 << 30},
+	}
+
+	observations := PVCQuotaObservations(providers)
+	if len(observations) != 1 {
+		t.Fatalf("got %d observations, want 1 (pv-plain has no quota metrics)", len(observations))
+	}
+	if _, ok := observations["pv-yrfs"]; !ok {
+		t.Fatalf("expected an observation for pv-yrfs, got %v", observations)
+	}
+}
+
+func TestPVCsOverQuota(t *testing.T) {
+	const oneGiB = 1 << 30
+	providers := map[string]volume.MetricsProvider{
+		"pv-tight": fakeQuotaMetricsProvider{quotaBytes: 10 * oneGiB, quotaBytesUsed: 10*oneGiB - 100<<20},
+		"pv-roomy": fakeQuotaMetricsProvider{quotaBytes: 10 * oneGiB, quotaBytesUsed: 1 * oneGiB},
+	}
+
+	observations := PVCQuotaObservations(providers)
+	threshold := *resource.NewQuantity(200<<20, resource.BinarySI)
+
+	overQuota := PVCsOverQuota(observations, threshold)
+	sort.Strings(overQuota)
+	if len(overQuota) != 1 || overQuota[0] != "pv-tight" {
+		t.Fatalf("got %v, want [pv-tight]", overQuota)
+	}
+}
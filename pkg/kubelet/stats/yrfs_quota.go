@@ -0,0 +1,105 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stats does not yet contain a cadvisor_stats_provider in this tree:
+// there is no ListPodStats/Summary API assembly here for the yrfs quota
+// stats below to be folded into. BuildVolumeStats is the entry point a
+// future VolumeStats builder would call for a yrfs-backed PV, exercised for
+// now only by this package's own tests.
+package stats
+
+import (
+	statsapi "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+	"k8s.io/kubernetes/pkg/volume"
+)
+
+// YRFSQuotaStats is a yrfs project quota's utilization for a single volume,
+// reported alongside a VolumeStats entry rather than folded into it: a
+// volume can be well under its statfs capacity while still over its project
+// quota, so the two must stay distinguishable to any consumer.
+type YRFSQuotaStats struct {
+	CapacityBytes  uint64
+	UsedBytes      uint64
+	AvailableBytes uint64
+	Inodes         uint64
+	InodesUsed     uint64
+	InodesFree     uint64
+}
+
+// BuildVolumeStats assembles the VolumeStats entry for a PV's
+// ListPodStats/Summary API output from the filesystem-wide Metrics every
+// MetricsProvider returns. It also returns the volume's yrfs project quota
+// utilization as a separate YRFSQuotaStats, non-nil only when provider
+// implements volume.QuotaMetricsProvider and reports a configured quota.
+func BuildVolumeStats(name string, provider volume.MetricsProvider) (statsapi.VolumeStats, *YRFSQuotaStats, error) {
+	metrics, err := provider.GetMetrics()
+	if err != nil {
+		return statsapi.VolumeStats{}, nil, err
+	}
+
+	vs := statsapi.VolumeStats{Name: name}
+	setFsStatsFromMetrics(&vs, metrics)
+
+	var quotaStats *YRFSQuotaStats
+	if quotaProvider, ok := provider.(volume.QuotaMetricsProvider); ok {
+		if qm, err := quotaProvider.GetQuotaMetrics(); err == nil {
+			quotaStats = yrfsQuotaStatsFromMetrics(qm)
+		}
+	}
+
+	return vs, quotaStats, nil
+}
+
+// setFsStatsFromMetrics populates vs's statfs-derived fields from metrics.
+func setFsStatsFromMetrics(vs *statsapi.VolumeStats, metrics *volume.Metrics) {
+	if metrics.Available != nil {
+		available := uint64(metrics.Available.Value())
+		vs.AvailableBytes = &available
+	}
+	if metrics.Capacity != nil {
+		capacity := uint64(metrics.Capacity.Value())
+		vs.CapacityBytes = &capacity
+	}
+	if metrics.Used != nil {
+		used := uint64(metrics.Used.Value())
+		vs.UsedBytes = &used
+	}
+	if metrics.Inodes != nil {
+		inodes := uint64(metrics.Inodes.Value())
+		vs.Inodes = &inodes
+	}
+	if metrics.InodesFree != nil {
+		inodesFree := uint64(metrics.InodesFree.Value())
+		vs.InodesFree = &inodesFree
+	}
+	if metrics.InodesUsed != nil {
+		inodesUsed := uint64(metrics.InodesUsed.Value())
+		vs.InodesUsed = &inodesUsed
+	}
+}
+
+// yrfsQuotaStatsFromMetrics converts a volume.QuotaMetrics reading into a
+// YRFSQuotaStats.
+func yrfsQuotaStatsFromMetrics(qm *volume.QuotaMetrics) *YRFSQuotaStats {
+	return &YRFSQuotaStats{
+		CapacityBytes:  uint64(qm.QuotaBytes.Value()),
+		UsedBytes:      uint64(qm.QuotaBytesUsed.Value()),
+		AvailableBytes: uint64(qm.QuotaBytes.Value() - qm.QuotaBytesUsed.Value()),
+		Inodes:         uint64(qm.QuotaInodes.Value()),
+		InodesUsed:     uint64(qm.QuotaInodesUsed.Value()),
+		InodesFree:     uint64(qm.QuotaInodes.Value() - qm.QuotaInodesUsed.Value()),
+	}
+}
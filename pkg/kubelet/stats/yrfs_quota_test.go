@@ -0,0 +1,97 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stats
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/volume"
+)
+
+type fakeMetricsProvider struct{}
+
+func (fakeMetricsProvider) GetMetrics() (*volume.Metrics, error) {
+	return &volume.Metrics{
+		Available:  resource.NewQuantity(1, resource.BinarySI),
+		Capacity:   resource.NewQuantity(10, resource.BinarySI),
+		Used:       resource.NewQuantity(9, resource.BinarySI),
+		Inodes:     resource.NewQuantity(100, resource.BinarySI),
+		InodesFree: resource.NewQuantity(10, resource.BinarySI),
+		InodesUsed: resource.NewQuantity(90, resource.BinarySI),
+	}, nil
+}
+
+type fakeQuotaMetricsProvider struct {
+	fakeMetricsProvider
+}
+
+func (fakeQuotaMetricsProvider) GetQuotaMetrics() (*volume.QuotaMetrics, error) {
+	return &volume.QuotaMetrics{
+		Time:            metav1.Now(),
+		QuotaBytes:      resource.NewQuantity(100, resource.BinarySI),
+		QuotaBytesUsed:  resource.NewQuantity(40, resource.BinarySI),
+		QuotaInodes:     resource.NewQuantity(1000, resource.BinarySI),
+		QuotaInodesUsed: resource.NewQuantity(300, resource.BinarySI),
+	}, nil
+}
+
+func TestBuildVolumeStatsReportsQuotaSeparately(t *testing.T) {
+	vs, quotaStats, err := BuildVolumeStats("pv-a", fakeQuotaMetricsProvider{})
+	if err != nil {
+		t.Fatalf("BuildVolumeStats: %v", err)
+	}
+	if vs.Name != "pv-a" {
+		t.Errorf("got Name %q, want pv-a", vs.Name)
+	}
+	if got := *vs.CapacityBytes; got != 10 {
+		t.Errorf("got CapacityBytes %d, want 10 (statfs capacity, unaffected by quota)", got)
+	}
+	if got := *vs.UsedBytes; got != 9 {
+		t.Errorf("got UsedBytes %d, want 9 (statfs usage, unaffected by quota)", got)
+	}
+
+	if quotaStats == nil {
+		t.Fatal("expected a non-nil YRFSQuotaStats for a QuotaMetricsProvider")
+	}
+	if quotaStats.CapacityBytes != 100 {
+		t.Errorf("got quota CapacityBytes %d, want 100", quotaStats.CapacityBytes)
+	}
+	if quotaStats.UsedBytes != 40 {
+		t.Errorf("got quota UsedBytes %d, want 40", quotaStats.UsedBytes)
+	}
+	if quotaStats.InodesUsed != 300 {
+		t.Errorf("got quota InodesUsed %d, want 300", quotaStats.InodesUsed)
+	}
+}
+
+func TestBuildVolumeStatsWithoutQuota(t *testing.T) {
+	vs, quotaStats, err := BuildVolumeStats("pv-b", fakeMetricsProvider{})
+	if err != nil {
+		t.Fatalf("BuildVolumeStats: %v", err)
+	}
+	if got := *vs.CapacityBytes; got != 10 {
+		t.Errorf("got CapacityBytes %d, want 10 (statfs capacity, no quota provider)", got)
+	}
+	if got := *vs.UsedBytes; got != 9 {
+		t.Errorf("got UsedBytes %d, want 9", got)
+	}
+	if quotaStats != nil {
+		t.Errorf("expected a nil YRFSQuotaStats without a QuotaMetricsProvider, got %+v", quotaStats)
+	}
+}
@@ -17,32 +17,83 @@ limitations under the License.
 package volume
 
 import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"golang.org/x/sys/unix"
+
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog"
 	"k8s.io/kubernetes/pkg/volume/util/yrfs"
 )
 
+// ErrNoQuotaConfigured is returned by GetQuotaMetrics for a yrfs volume whose
+// project_quota_info record carries no quota limit fields, as opposed to one
+// that reports a configured quota at some usage level.
+var ErrNoQuotaConfigured = errors.New("no yrfs project quota configured for this volume")
+
 var _ MetricsProvider = &metricsYRFS{}
 
+// xattrUsedBytes and xattrUsedInodes are the extended attributes some yrfs
+// mounts expose on every directory with the project's accounted usage, as
+// an alternative to looking the volume up in the /proc/fs/yrfs quota cache.
+const (
+	xattrUsedBytes  = "trusted.yrfs.project.used_bytes"
+	xattrUsedInodes = "trusted.yrfs.project.used_inodes"
+)
+
+// MetricsYRFSOptions configures optional fast paths for metricsYRFS.
+type MetricsYRFSOptions struct {
+	// PreferXattr, when true, tries to read usage from the
+	// trusted.yrfs.project.used_bytes/used_inodes extended attributes before
+	// falling back to the /proc/fs/yrfs quota cache. Only
+	// yrfs mounts that expose project-quota accounting through xattrs
+	// support this.
+	PreferXattr bool
+}
+
 // metricsDu represents a MetricsProvider that calculates the used and
 // available Volume space by calling fs.DiskUsage() and gathering
 // filesystem info for the Volume path.
 type metricsYRFS struct {
 	// the directory path the volume is mounted to.
-	pathTmp string
-	path    string
+	pathTmp    string
+	path       string
+	quotaCache *yrfs.QuotaCache
+	options    MetricsYRFSOptions
 }
 
 // NewMetricsYRFS creates a new metricsYRFS with the Volume path.
 // path: PersistentVolumePath
-func NewMetricsYRFS(pathTmp string, path string) MetricsProvider {
-	return &metricsYRFS{pathTmp,path}
+// ttl: how long the shared yrfs quota cache may serve a path's quota info
+// before re-reading /proc/fs/yrfs; a non-zero ttl changes the TTL for every
+// yrfs volume, and 0 leaves the shared cache's current TTL (initially
+// yrfs.DefaultQuotaCacheTTL) unchanged.
+func NewMetricsYRFS(pathTmp string, path string, ttl time.Duration) MetricsProvider {
+	return NewMetricsYRFSWithOptions(pathTmp, path, ttl, MetricsYRFSOptions{})
+}
+
+// NewMetricsYRFSWithOptions is like NewMetricsYRFS but lets cluster admins
+// opt a StorageClass into the xattr fast path via MetricsYRFSOptions.
+func NewMetricsYRFSWithOptions(pathTmp string, path string, ttl time.Duration, options MetricsYRFSOptions) MetricsProvider {
+	if ttl > 0 {
+		yrfs.SetQuotaCacheTTL(ttl)
+	}
+	return newMetricsYRFS(pathTmp, path, yrfs.DefaultQuotaCache(), options)
 }
 
-// GetMetrics calculates the volume usage and device free space by executing
-// "cat cat /proc/fs/yrfs/*/project_quota_info | grep path | awk '//{print $2 $3}'"
-// and gathering filesystem info for the Volume path.
+// newMetricsYRFS is like NewMetricsYRFSWithOptions but takes the
+// yrfs.QuotaCache directly, letting tests inject one built from a yrfs.FS
+// rooted at a testdata fixture instead of the process-wide default.
+func newMetricsYRFS(pathTmp string, path string, quotaCache *yrfs.QuotaCache, options MetricsYRFSOptions) MetricsProvider {
+	return &metricsYRFS{pathTmp, path, quotaCache, options}
+}
+
+// GetMetrics calculates the volume usage from the shared yrfs project quota
+// cache, or from the xattr fast path when options.PreferXattr is set, and
+// gathering filesystem info for the Volume path.
 // See MetricsProvider.GetMetrics
 func (md *metricsYRFS) GetMetrics() (*Metrics, error) {
 	metrics := &Metrics{Time: metav1.Now()}
@@ -50,19 +101,19 @@ func (md *metricsYRFS) GetMetrics() (*Metrics, error) {
 		return metrics, NewNoPathDefinedError()
 	}
 
-	err := md.runDiskUsage(metrics)
-	if err != nil {
-		klog.Error("[Volume] Failed to get disk usage, error: ", err)
-		return metrics, err
-	}
+	if !md.options.PreferXattr || !md.runXattrFastPath(metrics) {
+		if err := md.runDiskUsage(metrics); err != nil {
+			klog.Error("[Volume] Failed to get disk usage, error: ", err)
+			return metrics, err
+		}
 
-	err = md.runFind(metrics)
-	if err != nil {
-		klog.Error("[Volume] Failed to get disk inodes usage, error: ", err)
-		return metrics, err
+		if err := md.runFind(metrics); err != nil {
+			klog.Error("[Volume] Failed to get disk inodes usage, error: ", err)
+			return metrics, err
+		}
 	}
 
-	err = md.getFsInfo(metrics)
+	err := md.getFsInfo(metrics)
 	if err != nil {
 		klog.Error("[Volume] Failed to get disk fsInfo, error: ", err)
 		return metrics, err
@@ -71,23 +122,58 @@ func (md *metricsYRFS) GetMetrics() (*Metrics, error) {
 	return metrics, nil
 }
 
+// runXattrFastPath tries to populate metrics.Used and metrics.InodesUsed from
+// the trusted.yrfs.project.used_bytes/used_inodes extended attributes on
+// md.path, skipping the quota cache lookups. It reports
+// whether both attributes were read successfully.
+func (md *metricsYRFS) runXattrFastPath(metrics *Metrics) bool {
+	used, ok := getxattrUint64(md.path, xattrUsedBytes)
+	if !ok {
+		return false
+	}
+	inodesUsed, ok := getxattrUint64(md.path, xattrUsedInodes)
+	if !ok {
+		return false
+	}
+	metrics.Used = resource.NewQuantity(int64(used), resource.BinarySI)
+	metrics.InodesUsed = resource.NewQuantity(int64(inodesUsed), resource.BinarySI)
+	return true
+}
+
+// getxattr is unix.Getxattr, overridden in tests since the real syscall
+// needs a live "trusted." xattr on disk to exercise.
+var getxattr = unix.Getxattr
+
+// getxattrUint64 reads the little-endian uint64 stored in the named extended
+// attribute of path, reporting ok=false if the attribute is absent or not
+// exactly 8 bytes so callers can fall back to their slow path.
+func getxattrUint64(path, name string) (uint64, bool) {
+	buf := make([]byte, 8)
+	n, err := getxattr(path, name, buf)
+	if err != nil || n != 8 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(buf), true
+}
+
 // runDiskUsage gets disk usage of md.path and writes the results to metrics.Used
 func (md *metricsYRFS) runDiskUsage(metrics *Metrics) error {
-	used, err := yrfs.DiskUsage(md.pathTmp)
+	quota, err := md.quotaCache.Get(md.pathTmp)
 	if err != nil {
 		return err
 	}
-	metrics.Used = used
+	metrics.Used = resource.NewQuantity(int64(quota.Used), resource.BinarySI)
 	return nil
 }
 
-// runFind executes the "find" command and writes the results to metrics.InodesUsed
+// runFind reads the inode usage already accounted in the yrfs quota cache and
+// writes it to metrics.InodesUsed.
 func (md *metricsYRFS) runFind(metrics *Metrics) error {
-	inodesUsed, err := yrfs.Find(md.path)
+	quota, err := md.quotaCache.Get(md.pathTmp)
 	if err != nil {
 		return err
 	}
-	metrics.InodesUsed = resource.NewQuantity(inodesUsed, resource.BinarySI)
+	metrics.InodesUsed = resource.NewQuantity(quota.InodesUsed, resource.BinarySI)
 	return nil
 }
 
@@ -104,3 +190,55 @@ func (md *metricsYRFS) getFsInfo(metrics *Metrics) error {
 	metrics.InodesFree = resource.NewQuantity(inodesFree, resource.BinarySI)
 	return nil
 }
+
+var _ QuotaMetricsProvider = &metricsYRFS{}
+
+// QuotaMetricsProvider is implemented by MetricsProviders that can also
+// report quota-specific pressure signals distinct from filesystem-wide
+// capacity, such as a yrfs project quota. It is consulted, where supported,
+// in addition to the regular Metrics; see pkg/kubelet/eviction.PVCQuotaObservations
+// for the shape a caller would use to fold this into DiskPressure.
+type QuotaMetricsProvider interface {
+	GetQuotaMetrics() (*QuotaMetrics, error)
+}
+
+// QuotaMetrics holds the yrfs project-quota limits and usage for a volume.
+// It is reported alongside, not instead of, Metrics: a volume can be well
+// under its statfs capacity while still over its project quota.
+type QuotaMetrics struct {
+	// Time is the timestamp at which these stats were collected.
+	Time metav1.Time
+	// QuotaBytes is the hard byte limit of the volume's project quota.
+	QuotaBytes *resource.Quantity
+	// QuotaBytesUsed is the bytes currently counted against QuotaBytes.
+	QuotaBytesUsed *resource.Quantity
+	// QuotaInodes is the hard inode limit of the volume's project quota.
+	QuotaInodes *resource.Quantity
+	// QuotaInodesUsed is the inodes currently counted against QuotaInodes.
+	QuotaInodesUsed *resource.Quantity
+}
+
+// GetQuotaMetrics reports the project-quota limits and usage for the volume
+// from the shared yrfs quota cache. It returns ErrNoQuotaConfigured if the
+// volume's project_quota_info record has no quota limit fields, rather than
+// reporting a fabricated zero-byte quota as if the volume were out of space.
+func (md *metricsYRFS) GetQuotaMetrics() (*QuotaMetrics, error) {
+	quotaMetrics := &QuotaMetrics{Time: metav1.Now()}
+	if md.path == "" {
+		return quotaMetrics, NewNoPathDefinedError()
+	}
+
+	quota, err := md.quotaCache.Get(md.pathTmp)
+	if err != nil {
+		return quotaMetrics, err
+	}
+	if !quota.HasQuotaLimits {
+		return quotaMetrics, ErrNoQuotaConfigured
+	}
+
+	quotaMetrics.QuotaBytes = resource.NewQuantity(int64(quota.QuotaBytes), resource.BinarySI)
+	quotaMetrics.QuotaBytesUsed = resource.NewQuantity(int64(quota.Used), resource.BinarySI)
+	quotaMetrics.QuotaInodes = resource.NewQuantity(quota.QuotaInodes, resource.BinarySI)
+	quotaMetrics.QuotaInodesUsed = resource.NewQuantity(quota.InodesUsed, resource.BinarySI)
+	return quotaMetrics, nil
+}
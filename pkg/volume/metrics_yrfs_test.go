@@ -0,0 +1,86 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/kubernetes/pkg/volume/util/yrfs"
+)
+
+func TestMetricsYRFSGetQuotaMetrics(t *testing.T) {
+	fs, err := yrfs.NewFS(filepath.Join("util", "yrfs", "testdata", "fixtures", "limits"))
+	if err != nil {
+		t.Fatalf("yrfs.NewFS: %v", err)
+	}
+	cache := yrfs.NewQuotaCache(fs, time.Minute)
+
+	const path = "/var/lib/kubelet/pods/pv-quota"
+	provider := newMetricsYRFS(path, path, cache, MetricsYRFSOptions{})
+
+	quotaProvider, ok := provider.(QuotaMetricsProvider)
+	if !ok {
+		t.Fatal("metricsYRFS does not implement QuotaMetricsProvider")
+	}
+
+	qm, err := quotaProvider.GetQuotaMetrics()
+	if err != nil {
+		t.Fatalf("GetQuotaMetrics: %v", err)
+	}
+
+	const oneGiB = 1 << 30
+	if got, want := qm.QuotaBytes.Value(), int64(10*oneGiB); got != want {
+		t.Errorf("got QuotaBytes %d, want %d", got, want)
+	}
+	if got, want := qm.QuotaBytesUsed.Value(), int64(8*oneGiB); got != want {
+		t.Errorf("got QuotaBytesUsed %d, want %d", got, want)
+	}
+	if got, want := qm.QuotaInodes.Value(), int64(200000); got != want {
+		t.Errorf("got QuotaInodes %d, want %d", got, want)
+	}
+	if got, want := qm.QuotaInodesUsed.Value(), int64(150000); got != want {
+		t.Errorf("got QuotaInodesUsed %d, want %d", got, want)
+	}
+}
+
+func TestMetricsYRFSGetQuotaMetricsNoQuotaConfigured(t *testing.T) {
+	fs, err := yrfs.NewFS(filepath.Join("util", "yrfs", "testdata", "fixtures", "units"))
+	if err != nil {
+		t.Fatalf("yrfs.NewFS: %v", err)
+	}
+	cache := yrfs.NewQuotaCache(fs, time.Minute)
+
+	const path = "/var/lib/kubelet/pods/pv-bbb"
+	provider := newMetricsYRFS(path, path, cache, MetricsYRFSOptions{})
+	quotaProvider := provider.(QuotaMetricsProvider)
+
+	if _, err := quotaProvider.GetQuotaMetrics(); !errors.Is(err, ErrNoQuotaConfigured) {
+		t.Fatalf("got error %v, want ErrNoQuotaConfigured", err)
+	}
+}
+
+func TestMetricsYRFSGetQuotaMetricsNoPath(t *testing.T) {
+	provider := newMetricsYRFS("", "", yrfs.DefaultQuotaCache(), MetricsYRFSOptions{})
+	quotaProvider := provider.(QuotaMetricsProvider)
+
+	if _, err := quotaProvider.GetQuotaMetrics(); err == nil {
+		t.Fatal("expected NewNoPathDefinedError for an empty path, got nil")
+	}
+}
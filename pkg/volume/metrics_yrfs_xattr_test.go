@@ -0,0 +1,105 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volume
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestMetricsYRFSRunXattrFastPath(t *testing.T) {
+	defer func() { getxattr = unix.Getxattr }()
+
+	testCases := []struct {
+		name           string
+		stub           func(path, attr string, dest []byte) (int, error)
+		wantOK         bool
+		wantUsed       int64
+		wantInodesUsed int64
+	}{
+		{
+			name: "both attributes present",
+			stub: func(path, attr string, dest []byte) (int, error) {
+				var v uint64
+				switch attr {
+				case xattrUsedBytes:
+					v = 2048
+				case xattrUsedInodes:
+					v = 42
+				}
+				binary.LittleEndian.PutUint64(dest, v)
+				return 8, nil
+			},
+			wantOK:         true,
+			wantUsed:       2048,
+			wantInodesUsed: 42,
+		},
+		{
+			name: "used_bytes attribute missing falls back",
+			stub: func(path, attr string, dest []byte) (int, error) {
+				if attr == xattrUsedBytes {
+					return 0, unix.ENODATA
+				}
+				binary.LittleEndian.PutUint64(dest, 42)
+				return 8, nil
+			},
+			wantOK: false,
+		},
+		{
+			name: "used_inodes attribute missing falls back",
+			stub: func(path, attr string, dest []byte) (int, error) {
+				if attr == xattrUsedInodes {
+					return 0, unix.ENODATA
+				}
+				binary.LittleEndian.PutUint64(dest, 2048)
+				return 8, nil
+			},
+			wantOK: false,
+		},
+		{
+			name: "wrong-size attribute falls back",
+			stub: func(path, attr string, dest []byte) (int, error) {
+				return 4, nil
+			},
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			getxattr = tc.stub
+
+			md := &metricsYRFS{path: "/fake/path"}
+			metrics := &Metrics{}
+			ok := md.runXattrFastPath(metrics)
+			if ok != tc.wantOK {
+				t.Fatalf("got ok=%v, want %v", ok, tc.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got := metrics.Used.Value(); got != tc.wantUsed {
+				t.Errorf("got Used %d, want %d", got, tc.wantUsed)
+			}
+			if got := metrics.InodesUsed.Value(); got != tc.wantInodesUsed {
+				t.Errorf("got InodesUsed %d, want %d", got, tc.wantInodesUsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yrfs
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultQuotaCacheTTL is the TTL DefaultQuotaCache uses unless a caller
+// overrides it with SetQuotaCacheTTL.
+const DefaultQuotaCacheTTL = 10 * time.Second
+
+// QuotaCache is a TTL-bounded cache over FS.ProjectQuotaInfo. Every yrfs
+// volume on a node shares the same /proc/fs/yrfs/*/project_quota_info scan,
+// so a node with many yrfs volumes pays for one parse per TTL instead of one
+// per volume per polling interval.
+type QuotaCache struct {
+	fs  FS
+	ttl time.Duration
+
+	mu          sync.RWMutex
+	entries     map[string]ProjectQuota
+	lastRefresh time.Time
+}
+
+// NewQuotaCache creates a QuotaCache over fs that refreshes its contents at
+// most once per ttl.
+func NewQuotaCache(fs FS, ttl time.Duration) *QuotaCache {
+	return &QuotaCache{
+		fs:      fs,
+		ttl:     ttl,
+		entries: make(map[string]ProjectQuota),
+	}
+}
+
+var (
+	defaultQuotaCache     *QuotaCache
+	defaultQuotaCacheOnce sync.Once
+)
+
+// DefaultQuotaCache returns the process-wide QuotaCache rooted at
+// DefaultMountPoint, creating it with DefaultQuotaCacheTTL on first use.
+func DefaultQuotaCache() *QuotaCache {
+	defaultQuotaCacheOnce.Do(func() {
+		fs, err := NewFS(DefaultMountPoint)
+		if err != nil {
+			// DefaultMountPoint may not exist on a node with no yrfs volumes
+			// mounted; let the first Get surface the error instead of
+			// failing at startup.
+			fs = FS{root: DefaultMountPoint}
+		}
+		defaultQuotaCache = NewQuotaCache(fs, DefaultQuotaCacheTTL)
+	})
+	return defaultQuotaCache
+}
+
+// SetQuotaCacheTTL changes the TTL of the process-wide DefaultQuotaCache.
+// Callers should set it once, during startup.
+func SetQuotaCacheTTL(ttl time.Duration) {
+	cache := DefaultQuotaCache()
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.ttl = ttl
+}
+
+// Get returns the ProjectQuota for pvPath, refreshing the cache first if it
+// has no entry for pvPath or is older than the configured TTL.
+func (c *QuotaCache) Get(pvPath string) (ProjectQuota, error) {
+	c.mu.RLock()
+	quota, ok := c.entries[pvPath]
+	stale := time.Since(c.lastRefresh) > c.ttl
+	c.mu.RUnlock()
+
+	if ok && !stale {
+		quotaCacheHits.Inc()
+		return quota, nil
+	}
+	quotaCacheMisses.Inc()
+
+	if err := c.refresh(); err != nil {
+		return ProjectQuota{}, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	quota, ok = c.entries[pvPath]
+	if !ok {
+		return ProjectQuota{}, fmt.Errorf("failed to find pvPath %s in %s", pvPath, c.fs.projectQuotaInfoGlob())
+	}
+	return quota, nil
+}
+
+// refresh re-reads every project_quota_info file and repopulates the cache.
+func (c *QuotaCache) refresh() error {
+	start := time.Now()
+	quotas, err := c.fs.ProjectQuotaInfo()
+	quotaParseDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		return err
+	}
+
+	entries := make(map[string]ProjectQuota, len(quotas))
+	for _, quota := range quotas {
+		entries[quota.PvPath] = quota
+	}
+
+	c.mu.Lock()
+	c.entries = entries
+	c.lastRefresh = time.Now()
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yrfs
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newUnitsQuotaCache(t *testing.T, ttl time.Duration) *QuotaCache {
+	t.Helper()
+	fs, err := NewFS(filepath.Join("testdata", "fixtures", "units"))
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+	return NewQuotaCache(fs, ttl)
+}
+
+func TestQuotaCacheGet(t *testing.T) {
+	cache := newUnitsQuotaCache(t, time.Minute)
+
+	quota, err := cache.Get("/var/lib/kubelet/pods/pv-bbb")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	wantUsed := 512 * 1024.0 * 1024
+	if quota.Used != wantUsed {
+		t.Errorf("got Used %v, want %v", quota.Used, wantUsed)
+	}
+
+	if _, err := cache.Get("/does/not/exist"); err == nil {
+		t.Fatal("expected an error for a missing pvPath, got nil")
+	}
+}
+
+func TestQuotaCacheServesWithinTTL(t *testing.T) {
+	cache := newUnitsQuotaCache(t, time.Minute)
+
+	if _, err := cache.Get("/var/lib/kubelet/pods/pv-bbb"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	first := cache.lastRefresh
+
+	if _, err := cache.Get("/var/lib/kubelet/pods/pv-bbb"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	second := cache.lastRefresh
+
+	if !second.Equal(first) {
+		t.Fatalf("expected a cached entry within the TTL to be served without a refresh, got refreshed at %v then %v", first, second)
+	}
+}
+
+func TestQuotaCacheRefreshesAfterTTL(t *testing.T) {
+	cache := newUnitsQuotaCache(t, time.Millisecond)
+
+	if _, err := cache.Get("/var/lib/kubelet/pods/pv-bbb"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	first := cache.lastRefresh
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, err := cache.Get("/var/lib/kubelet/pods/pv-bbb"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	second := cache.lastRefresh
+
+	if !second.After(first) {
+		t.Fatalf("expected a stale entry past the TTL to trigger a refresh, got the same refresh time %v", first)
+	}
+}
+
+func TestQuotaCacheConcurrentGet(t *testing.T) {
+	cache := newUnitsQuotaCache(t, time.Millisecond)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.Get("/var/lib/kubelet/pods/pv-bbb"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent Get failed: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yrfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultMountPoint is the common mount point of the /proc/fs/yrfs pseudo
+// filesystem exposed by a yrfs-aware kernel.
+const DefaultMountPoint = "/proc/fs/yrfs"
+
+// FS represents a mounted /proc/fs/yrfs pseudo filesystem, following the
+// typed-root-plus-parser-methods pattern of github.com/prometheus/procfs.
+// Using a typed handle instead of hard-coded glob paths lets callers point
+// it at a testdata fixture tree in tests.
+type FS struct {
+	root string
+}
+
+// NewFS returns a new FS mounted under the given root, such as
+// DefaultMountPoint. It returns an error if root does not exist.
+func NewFS(root string) (FS, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return FS{}, fmt.Errorf("could not open yrfs root %q: %v", root, err)
+	}
+	if !info.IsDir() {
+		return FS{}, fmt.Errorf("yrfs root %q is not a directory", root)
+	}
+	return FS{root: root}, nil
+}
+
+// projectQuotaInfoGlob is the glob pattern matching every mounted yrfs
+// instance's project_quota_info file under fs.root.
+func (fs FS) projectQuotaInfoGlob() string {
+	return filepath.Join(fs.root, "*", "project_quota_info")
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yrfs
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNewFS(t *testing.T) {
+	testCases := []struct {
+		name    string
+		root    string
+		wantErr bool
+	}{
+		{name: "existing root", root: filepath.Join("testdata", "fixtures", "units")},
+		{name: "missing root", root: filepath.Join("testdata", "does-not-exist"), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewFS(tc.root)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewFS(%q) error = %v, wantErr %v", tc.root, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestFSProjectQuotaInfo(t *testing.T) {
+	fs, err := NewFS(filepath.Join("testdata", "fixtures", "units"))
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+
+	quotas, err := fs.ProjectQuotaInfo()
+	if err != nil {
+		t.Fatalf("ProjectQuotaInfo: %v", err)
+	}
+
+	want := map[string]float64{
+		"/var/lib/kubelet/pods/pv-aaa":   100 * 1024,
+		"/var/lib/kubelet/pods/pv-bbb":   512 * 1024 * 1024,
+		"/var/lib/kubelet/pods/pv-ccc":   2 * 1024 * 1024 * 1024,
+		"/var/lib/kubelet/pods/pv-ddd":   1 * 1024 * 1024 * 1024 * 1024,
+		"/var/lib/kubelet/pods/pv-empty": 0,
+	}
+	if len(quotas) != len(want) {
+		t.Fatalf("got %d records, want %d", len(quotas), len(want))
+	}
+	for _, quota := range quotas {
+		wantUsed, ok := want[quota.PvPath]
+		if !ok {
+			t.Errorf("unexpected pvPath %q", quota.PvPath)
+			continue
+		}
+		if quota.Used != wantUsed {
+			t.Errorf("pvPath %q: got Used %v, want %v", quota.PvPath, quota.Used, wantUsed)
+		}
+	}
+}
+
+func TestFSQuotaForPath(t *testing.T) {
+	fs, err := NewFS(filepath.Join("testdata", "fixtures", "units"))
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+
+	if _, err := fs.QuotaForPath("/does/not/exist"); err == nil {
+		t.Fatal("expected an error for a missing pvPath, got nil")
+	}
+
+	quota, err := fs.QuotaForPath("/var/lib/kubelet/pods/pv-empty")
+	if err != nil {
+		t.Fatalf("QuotaForPath: %v", err)
+	}
+	if quota.Used != 0 {
+		t.Errorf("got Used %v, want 0", quota.Used)
+	}
+}
+
+func TestFSProjectQuotaInfoLimits(t *testing.T) {
+	fs, err := NewFS(filepath.Join("testdata", "fixtures", "limits"))
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+
+	quota, err := fs.QuotaForPath("/var/lib/kubelet/pods/pv-quota")
+	if err != nil {
+		t.Fatalf("QuotaForPath: %v", err)
+	}
+
+	wantQuotaBytes := 10 * 1024.0 * 1024 * 1024
+	if quota.QuotaBytes != wantQuotaBytes {
+		t.Errorf("got QuotaBytes %v, want %v", quota.QuotaBytes, wantQuotaBytes)
+	}
+	if quota.QuotaInodes != 200000 {
+		t.Errorf("got QuotaInodes %v, want 200000", quota.QuotaInodes)
+	}
+}
+
+func TestFSProjectQuotaInfoMalformed(t *testing.T) {
+	fs, err := NewFS(filepath.Join("testdata", "fixtures", "malformed"))
+	if err != nil {
+		t.Fatalf("NewFS: %v", err)
+	}
+
+	quotas, err := fs.ProjectQuotaInfo()
+	if err != nil {
+		t.Fatalf("ProjectQuotaInfo: %v", err)
+	}
+	if len(quotas) != 1 {
+		t.Fatalf("got %d records, want 1 (the malformed line should be skipped, not fail the scan)", len(quotas))
+	}
+	if quotas[0].PvPath != "/var/lib/kubelet/pods/pv-aaa" {
+		t.Errorf("got pvPath %q, want /var/lib/kubelet/pods/pv-aaa", quotas[0].PvPath)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yrfs
+
+import (
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	quotaParseDuration = k8smetrics.NewHistogram(&k8smetrics.HistogramOpts{
+		Subsystem:      "yrfs",
+		Name:           "quota_parse_duration_seconds",
+		Help:           "Duration in seconds of reading and parsing all project_quota_info files.",
+		Buckets:        k8smetrics.DefBuckets,
+		StabilityLevel: k8smetrics.ALPHA,
+	})
+	quotaCacheHits = k8smetrics.NewCounter(&k8smetrics.CounterOpts{
+		Subsystem:      "yrfs",
+		Name:           "quota_cache_hits_total",
+		Help:           "Number of QuotaCache lookups served without a /proc re-read.",
+		StabilityLevel: k8smetrics.ALPHA,
+	})
+	quotaCacheMisses = k8smetrics.NewCounter(&k8smetrics.CounterOpts{
+		Subsystem:      "yrfs",
+		Name:           "quota_cache_misses_total",
+		Help:           "Number of QuotaCache lookups that triggered a /proc re-read.",
+		StabilityLevel: k8smetrics.ALPHA,
+	})
+)
+
+func init() {
+	legacyregistry.MustRegister(quotaParseDuration)
+	legacyregistry.MustRegister(quotaCacheHits)
+	legacyregistry.MustRegister(quotaCacheMisses)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2014 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yrfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog"
+)
+
+// ProjectQuota is a single parsed record from a project_quota_info file.
+type ProjectQuota struct {
+	// PvPath is the mount path of the PersistentVolume the record applies to.
+	PvPath string
+	// Used is the accounted space usage, converted to bytes.
+	Used float64
+	// Unit is the unit the record reported Used in before conversion (KiB,
+	// MiB, GiB or TiB).
+	Unit string
+	// InodesUsed is the number of inodes accounted against the quota.
+	InodesUsed int64
+	// QuotaBytes is the hard byte limit configured for the project quota,
+	// converted to bytes. It is 0 if the record does not report one.
+	QuotaBytes float64
+	// QuotaInodes is the hard inode limit configured for the project quota.
+	// It is 0 if the record does not report one.
+	QuotaInodes int64
+	// HasQuotaLimits reports whether this record carried the optional
+	// quotaBytes/quotaUnit/quotaInodes fields at all, distinguishing a PV with
+	// no project quota configured (QuotaBytes and QuotaInodes both 0) from
+	// one whose quota happens to be exhausted.
+	HasQuotaLimits bool
+}
+
+// ReadProjectQuotaInfo parses every project_quota_info file under
+// DefaultMountPoint. Most callers should go through DefaultQuotaCache
+// instead, which adds TTL-bounded caching on top of this.
+func ReadProjectQuotaInfo() ([]ProjectQuota, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ProjectQuotaInfo()
+}
+
+// ProjectQuotaInfo parses every project_quota_info file under fs.root.
+func (fs FS) ProjectQuotaInfo() ([]ProjectQuota, error) {
+	glob := fs.projectQuotaInfoGlob()
+	paths, err := filepath.Glob(glob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to glob %s: %v", glob, err)
+	}
+
+	var quotas []ProjectQuota
+	for _, path := range paths {
+		records, err := readProjectQuotaInfoFile(path)
+		if err != nil {
+			return nil, err
+		}
+		quotas = append(quotas, records...)
+	}
+	return quotas, nil
+}
+
+// QuotaForPath returns the ProjectQuota record for pvPath, or an error if no
+// record under fs.root matches.
+func (fs FS) QuotaForPath(pvPath string) (ProjectQuota, error) {
+	quotas, err := fs.ProjectQuotaInfo()
+	if err != nil {
+		return ProjectQuota{}, err
+	}
+	for _, quota := range quotas {
+		if quota.PvPath == pvPath {
+			return quota, nil
+		}
+	}
+	return ProjectQuota{}, fmt.Errorf("failed to find pvPath %s under %s", pvPath, fs.root)
+}
+
+// readProjectQuotaInfoFile parses a single project_quota_info file. A
+// malformed record is logged and skipped rather than failing the file,
+// since it is only one yrfs volume out of the whole-node scan ProjectQuotaInfo
+// performs; the original per-path grep only ever failed the one path it was
+// looking up, and this preserves that fault isolation.
+func readProjectQuotaInfoFile(path string) ([]ProjectQuota, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var quotas []ProjectQuota
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		quota, err := parseProjectQuotaLine(line)
+		if err != nil {
+			klog.Warningf("Skipping malformed project_quota_info record in %s: %v", path, err)
+			continue
+		}
+		quotas = append(quotas, quota)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	return quotas, nil
+}
+
+// parseProjectQuotaLine parses a single non-empty, non-comment line of a
+// project_quota_info file:
+// "pvPath used unit [inodesUsed [quotaBytes quotaUnit quotaInodes]]".
+func parseProjectQuotaLine(line string) (ProjectQuota, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return ProjectQuota{}, fmt.Errorf("malformed project_quota_info record: %q", line)
+	}
+
+	used, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return ProjectQuota{}, fmt.Errorf("invalid used value %q: %v", fields[1], err)
+	}
+
+	quota := ProjectQuota{
+		PvPath: fields[0],
+		Used:   used * unitMultiplier(fields[2]),
+		Unit:   fields[2],
+	}
+
+	if len(fields) >= 4 {
+		inodesUsed, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return ProjectQuota{}, fmt.Errorf("invalid inodesUsed value %q: %v", fields[3], err)
+		}
+		quota.InodesUsed = inodesUsed
+	}
+
+	if len(fields) >= 7 {
+		quotaBytes, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return ProjectQuota{}, fmt.Errorf("invalid quotaBytes value %q: %v", fields[4], err)
+		}
+		quota.QuotaBytes = quotaBytes * unitMultiplier(fields[5])
+
+		quotaInodes, err := strconv.ParseInt(fields[6], 10, 64)
+		if err != nil {
+			return ProjectQuota{}, fmt.Errorf("invalid quotaInodes value %q: %v", fields[6], err)
+		}
+		quota.QuotaInodes = quotaInodes
+		quota.HasQuotaLimits = true
+	}
+
+	return quota, nil
+}
+
+// unitMultiplier converts a project_quota_info unit suffix into a byte
+// multiplier.
+func unitMultiplier(unit string) float64 {
+	switch unit {
+	case "KiB":
+		return 1024
+	case "MiB":
+		return 1024 * 1024
+	case "GiB":
+		return 1024 * 1024 * 1024
+	case "TiB":
+		return 1024 * 1024 * 1024 * 1024
+	default:
+		return 1
+	}
+}